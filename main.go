@@ -6,22 +6,30 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/yildizozan/kubemcp/pkg/auth"
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+	"github.com/yildizozan/kubemcp/pkg/metrics"
+	"github.com/yildizozan/kubemcp/pkg/tools/apply"
+	"github.com/yildizozan/kubemcp/pkg/tools/dynamic"
+	"github.com/yildizozan/kubemcp/pkg/tools/stream"
+	"github.com/yildizozan/kubemcp/pkg/watch"
 )
 
 var (
-	clientset *kubernetes.Clientset
-	registry  = prometheus.NewRegistry()
+	clusters     *cluster.Registry
+	watchManager *watch.Manager
+	authGate     = auth.NewGate(os.Getenv("KUBEMCP_REQUIRE_IDENTITY") != "false")
+	registry     = prometheus.NewRegistry()
 
 	activeConnections = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "mcp_active_connections",
@@ -40,38 +48,17 @@ func init() {
 }
 
 func main() {
-	var config *rest.Config
-	var err error
+	m := metrics.New(registry)
 
-	// Önce in-cluster config deneyelim
-	config, err = rest.InClusterConfig()
-	if err != nil {
-		log.Println("In-cluster config bulunamadı, kubeconfig dosyası deneniyor...")
-
-		// KUBECONFIG environment variable'ını kontrol et
-		kubeconfigPath := os.Getenv("KUBECONFIG")
-		if kubeconfigPath == "" {
-			// Varsayılan home directory kubeconfig path
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				panic(fmt.Sprintf("Home directory bulunamadı: %v", err))
-			}
-			kubeconfigPath = filepath.Join(homeDir, ".kube", "config")
-		}
-
-		// Kubeconfig dosyasından config yükle
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-		if err != nil {
-			panic(fmt.Sprintf("Kubeconfig yüklenemedi: %v", err))
-		}
-		log.Printf("Kubeconfig kullanılıyor: %s", kubeconfigPath)
-	} else {
-		log.Println("In-cluster config kullanılıyor")
-	}
-	clientset, err = kubernetes.NewForConfig(config)
+	var err error
+	clusters, err = cluster.NewRegistry(os.Getenv("KUBECONFIG"), m.WrapTransport)
 	if err != nil {
-		panic(fmt.Sprintf("Kubernetes clientset oluşturulamadı: %v", err))
+		panic(fmt.Sprintf("Cluster registry oluşturulamadı: %v", err))
 	}
+	log.Printf("Kayıtlı cluster'lar: %v", clusters.List())
+
+	dynamicTools := dynamic.NewRegistrar(clusters, m, authGate)
+	applyTools := apply.NewRegistrar(clusters, m, authGate)
 
 	s := server.NewMCPServer(
 		"Kubernetes MCP Server",
@@ -79,10 +66,14 @@ func main() {
 		server.WithToolCapabilities(true),
 	)
 
-	// Create SSE server for MCP with options
+	// Create SSE server for MCP with options. WithSSEContextFunc attaches the
+	// caller's impersonation identity (bearer token or X-Impersonate-*
+	// headers) to the request context so tool handlers can gate on it via
+	// authGate.
 	sseServer := server.NewSSEServer(s,
 		server.WithSSEEndpoint("/sse"),
 		server.WithMessageEndpoint("/message"),
+		server.WithSSEContextFunc(auth.SSEContextFunc),
 	)
 
 	toolGetPodDetails := mcp.NewTool("get_pod_details",
@@ -95,6 +86,9 @@ func main() {
 			mcp.Description("Namespace (varsayılan: default)"),
 			mcp.DefaultString("default"),
 		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
 	)
 
 	toolPodsGetByLabel := mcp.NewTool("get_pods_by_label",
@@ -103,10 +97,41 @@ func main() {
 			mcp.Description("Label seçici (örneğin: app=nginx veya app.kubernetes.io/instance=nginx)"),
 			mcp.Required(),
 		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
 	)
 
-	s.AddTool(toolGetPodDetails, getPodDatailsHandler)
-	s.AddTool(toolPodsGetByLabel, getPodByLabelHandler)
+	s.AddTool(toolGetPodDetails, m.Wrap("get_pod_details", getPodDatailsHandler))
+	s.AddTool(toolPodsGetByLabel, m.Wrap("get_pods_by_label", getPodByLabelHandler))
+
+	// list_clusters / use_cluster: çoklu cluster desteği
+	clusters.Register(s)
+
+	// Discovery/DynamicClient destekli genel kaynak araçları (list_resources,
+	// get_resource, describe_resource, get_events_for_object)
+	dynamicTools.Register(s)
+
+	// Informer cache yöneticisini kur (her cluster'ın informer'ları o cluster'ı
+	// hedefleyen ilk çağrıda başlatılır) ve watch_resource aracını ekle; aynı cache,
+	// get_pod_details/get_pods_by_label'ı API sunucusuna gitmeden yanıtlamak
+	// için de kullanılır (bkz. watchManager.PodLister çağrıları)
+	watchManager = watch.NewManager(clusters, s, m, authGate)
+	if err := watchManager.Start(context.Background()); err != nil {
+		panic(fmt.Sprintf("Watch manager başlatılamadı: %v", err))
+	}
+	watchManager.Register(s)
+
+	// kube_apply / kube_delete: manifest uygulama ve session bazlı temizlik
+	applyTools.Register(s)
+
+	// get_pod_logs / exec_in_pod: ilerleme bildirimleriyle akan log/exec çıktısı
+	streamTools := stream.NewRegistrar(clusters, s, m, registry, authGate)
+	streamTools.Register(s)
+
+	// Kubeconfig'teki token/exec-plugin rotasyonunu takip et, böylece uzun
+	// ömürlü SSE oturumları kimlik bilgisi yenilenmesinden etkilenmez
+	go clusters.WatchForRotation(context.Background(), 30*time.Second)
 
 	// Setup HTTP mux
 	mux := http.NewServeMux()
@@ -114,6 +139,29 @@ func main() {
 	// Add Prometheus metrics endpoint
 	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
+	// /healthz: probes the default cluster's API server via a discovery call
+	mux.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := clusters.Ping(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "unhealthy: %v\n", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	}))
+
+	// /readyz: probes the default cluster's API server so the pod is taken out
+	// of service when the cluster it talks to is unreachable
+	mux.Handle("/readyz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := clusters.Ping(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "not ready: %v\n", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	}))
+
 	// Add SSE endpoints for MCP with connection tracking
 	mux.Handle("/sse", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		activeConnections.Inc()
@@ -145,6 +193,7 @@ func main() {
 	log.Printf("SSE endpoint: :%s/sse", addr)
 	log.Printf("Message endpoint: :%s/message", addr)
 	log.Printf("Metrics endpoint: :%s/metrics", addr)
+	log.Printf("Health endpoints: :%s/healthz, :%s/readyz", addr, addr)
 	log.Printf("Root endpoint (MCP): :%s/", addr)
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -166,28 +215,48 @@ func getPodByLabelHandler(ctx context.Context, request mcp.CallToolRequest) (*mc
 		return mcp.NewToolResultError("Invalid arguments format"), nil
 	}
 
+	c, err := clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	labelSelector, _ := args["labelSelector"].(string)
 	if labelSelector == "" {
 		return mcp.NewToolResultError("labelSelector is required"), nil
 	}
 
+	if err := authGate.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Verb:     "list",
+		Resource: "pods",
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	log.Println(labelSelector)
 
-	listOptions := metav1.ListOptions{
-		LabelSelector: labelSelector,
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid labelSelector: %v", err)), nil
 	}
-	pods, err := clientset.CoreV1().Pods("").List(ctx, listOptions)
+
+	podLister, err := watchManager.PodLister(c)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Pod cache hazır değil: %v", err)), nil
+	}
+	cachedPods, err := podLister.List(selector)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Error getting pods list: %v", err)), nil
 	}
 
-	log.Printf("Found %d pods", len(pods.Items))
+	log.Printf("Found %d pods", len(cachedPods))
 
-	for i := range pods.Items {
-		deleteUnnecessaryFieldsFromPodSpec(&pods.Items[i])
+	pods := make([]v1.Pod, len(cachedPods))
+	for i, p := range cachedPods {
+		pods[i] = *p.DeepCopy()
+		deleteUnnecessaryFieldsFromPodSpec(&pods[i])
 	}
 
-	return mcp.NewToolResultJSON(pods.Items)
+	return mcp.NewToolResultJSON(pods)
 }
 
 func getPodDatailsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -196,6 +265,11 @@ func getPodDatailsHandler(ctx context.Context, request mcp.CallToolRequest) (*mc
 		return mcp.NewToolResultError("Invalid arguments format"), nil
 	}
 
+	c, err := clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	podName, _ := args["podName"].(string)
 	namespace, _ := args["namespace"].(string)
 
@@ -203,10 +277,24 @@ func getPodDatailsHandler(ctx context.Context, request mcp.CallToolRequest) (*mc
 		namespace = "default"
 	}
 
-	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err := authGate.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "get",
+		Resource:  "pods",
+		Name:      podName,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	podLister, err := watchManager.PodLister(c)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Pod cache hazır değil: %v", err)), nil
+	}
+	cachedPod, err := podLister.Pods(namespace).Get(podName)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Pod not found: %v", err)), nil
 	}
+	pod := cachedPod.DeepCopy()
 
 	deleteUnnecessaryFieldsFromPodSpec(pod)
 
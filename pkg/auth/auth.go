@@ -0,0 +1,132 @@
+// Package auth extracts a caller identity from the SSE transport and checks
+// it against the Kubernetes API server's own RBAC via SelfSubjectAccessReview,
+// so kubemcp's tools never return more than the caller's own permissions
+// would allow in a multi-tenant deployment where many human users share one
+// kubemcp instance.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+)
+
+// Identity is the caller's identity as propagated through the SSE transport:
+// either a bearer token to impersonate verbatim, or an explicit
+// X-Impersonate-User/X-Impersonate-Group pair layered on top of kubemcp's
+// own credentials.
+type Identity struct {
+	Token             string
+	ImpersonateUser   string
+	ImpersonateGroups []string
+}
+
+type contextKey struct{}
+
+// SSEContextFunc reads the Authorization and X-Impersonate-* headers off the
+// incoming SSE/message request and attaches the resulting Identity to ctx, so
+// tool handlers on the same session can recover it via FromContext. Pass it
+// to server.WithSSEContextFunc.
+func SSEContextFunc(ctx context.Context, r *http.Request) context.Context {
+	identity := Identity{
+		ImpersonateUser:   r.Header.Get("X-Impersonate-User"),
+		ImpersonateGroups: r.Header.Values("X-Impersonate-Group"),
+	}
+	if authHeader := r.Header.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		identity.Token = strings.TrimPrefix(authHeader, "Bearer ")
+	}
+
+	if identity.Token == "" && identity.ImpersonateUser == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, &identity)
+}
+
+// FromContext returns the Identity attached by SSEContextFunc, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(*Identity)
+	return identity, ok
+}
+
+// impersonatingConfig returns a copy of base whose credentials are replaced
+// by identity: either the bearer token the caller supplied, or an
+// Impersonate-User/Group pair layered on top of kubemcp's own credentials.
+func impersonatingConfig(base *rest.Config, identity *Identity) *rest.Config {
+	cfg := rest.CopyConfig(base)
+	if identity.Token != "" {
+		cfg.BearerToken = identity.Token
+		cfg.BearerTokenFile = ""
+		cfg.Username = ""
+		cfg.Password = ""
+		cfg.TLSClientConfig.CertFile = ""
+		cfg.TLSClientConfig.KeyFile = ""
+		cfg.TLSClientConfig.CertData = nil
+		cfg.TLSClientConfig.KeyData = nil
+		cfg.ExecProvider = nil
+		cfg.AuthProvider = nil
+		cfg.Impersonate = rest.ImpersonationConfig{}
+		return cfg
+	}
+
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: identity.ImpersonateUser,
+		Groups:   identity.ImpersonateGroups,
+	}
+	return cfg
+}
+
+// Gate issues a SelfSubjectAccessReview against the caller's identity before
+// a tool is allowed to act on a cluster.
+type Gate struct {
+	requireIdentity bool
+}
+
+// NewGate builds a Gate. When requireIdentity is true, Check denies any call
+// whose ctx carries no Identity instead of silently falling back to
+// kubemcp's own credentials; set it false only for single-tenant
+// deployments that have no per-caller identity to gate on.
+func NewGate(requireIdentity bool) *Gate {
+	return &Gate{requireIdentity: requireIdentity}
+}
+
+// Check issues a SelfSubjectAccessReview for attrs against c, impersonating
+// whatever Identity is attached to ctx. When ctx carries no Identity (the
+// caller didn't supply one) and the Gate requires one, the call is denied;
+// otherwise it's treated the same as single-tenant access and allowed.
+func (g *Gate) Check(ctx context.Context, c *cluster.Cluster, attrs authorizationv1.ResourceAttributes) error {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		if g.requireIdentity {
+			return fmt.Errorf("erişim reddedildi: çağıran kimliği yok (Authorization: Bearer ya da X-Impersonate-User başlığı gerekli)")
+		}
+		return nil
+	}
+
+	cfg := impersonatingConfig(c.RestConfig, identity)
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("yetki kontrolü için client oluşturulamadı: %w", err)
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+		},
+	}
+	result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("yetki kontrolü yapılamadı: %w", err)
+	}
+	if !result.Status.Allowed {
+		return fmt.Errorf("erişim reddedildi: %s %s için yetkiniz yok (namespace: %q)", attrs.Verb, attrs.Resource, attrs.Namespace)
+	}
+	return nil
+}
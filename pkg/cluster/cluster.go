@@ -0,0 +1,332 @@
+// Package cluster loads every context out of a kubeconfig (or falls back to
+// in-cluster config) into a Registry, so a single kubemcp instance can serve
+// many clusters instead of the one global clientset main() used to build.
+// Handlers accept an optional `cluster` argument and resolve it through
+// Registry.Resolve; an empty value falls back to the calling session's
+// use_cluster choice, or else the kubeconfig's current context.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Cluster bundles every client built from a single kubeconfig context (or the
+// in-cluster config).
+type Cluster struct {
+	Name       string
+	RestConfig *rest.Config
+	Clientset  kubernetes.Interface
+	Dynamic    dynamic.Interface
+	Discovery  discovery.DiscoveryInterface
+	Mapper     *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func buildCluster(name string, config *rest.Config, wrapTransport func(http.RoundTripper) http.RoundTripper) (*Cluster, error) {
+	if wrapTransport != nil {
+		config.WrapTransport = wrapTransport
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("clientset oluşturulamadı (%s): %w", name, err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client oluşturulamadı (%s): %w", name, err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("discovery client oluşturulamadı (%s): %w", name, err)
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	return &Cluster{
+		Name:       name,
+		RestConfig: config,
+		Clientset:  clientset,
+		Dynamic:    dynamicClient,
+		Discovery:  discoveryClient,
+		Mapper:     mapper,
+	}, nil
+}
+
+// Registry holds every known cluster, keyed by kubeconfig context name, plus
+// the name that handlers fall back to when no `cluster` argument is given.
+// That fallback is tracked per MCP session (sessionDefault), not just
+// registry-wide (defaultName), since one registry serves many concurrent SSE
+// sessions and use_cluster must only redirect the session that called it.
+type Registry struct {
+	mu             sync.RWMutex
+	clusters       map[string]*Cluster
+	defaultName    string
+	sessionDefault map[string]string
+	kubeconfigPath string
+	wrapTransport  func(http.RoundTripper) http.RoundTripper
+}
+
+// NewRegistry builds a Registry. If kubeconfigPath is empty and an in-cluster
+// config is available, the registry contains a single "in-cluster" entry.
+// Otherwise every context in the kubeconfig is loaded eagerly. wrapTransport,
+// when non-nil, is installed as every cluster's rest.Config.WrapTransport
+// (e.g. to account Kubernetes API calls in Prometheus metrics).
+func NewRegistry(kubeconfigPath string, wrapTransport func(http.RoundTripper) http.RoundTripper) (*Registry, error) {
+	r := &Registry{
+		clusters:       make(map[string]*Cluster),
+		sessionDefault: make(map[string]string),
+		kubeconfigPath: kubeconfigPath,
+		wrapTransport:  wrapTransport,
+	}
+
+	if kubeconfigPath == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			c, err := buildCluster("in-cluster", config, wrapTransport)
+			if err != nil {
+				return nil, err
+			}
+			r.clusters["in-cluster"] = c
+			r.defaultName = "in-cluster"
+			return r, nil
+		}
+		log.Println("In-cluster config bulunamadı, kubeconfig dosyası deneniyor...")
+
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("home directory bulunamadı: %w", err)
+		}
+		r.kubeconfigPath = filepath.Join(homeDir, ".kube", "config")
+	}
+
+	if err := r.loadFromKubeconfig(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Registry) loadFromKubeconfig() error {
+	rawConfig, err := clientcmd.LoadFromFile(r.kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("kubeconfig yüklenemedi: %w", err)
+	}
+
+	clusters := make(map[string]*Cluster, len(rawConfig.Contexts))
+	for contextName := range rawConfig.Contexts {
+		clientConfig := clientcmd.NewNonInteractiveClientConfig(*rawConfig, contextName, &clientcmd.ConfigOverrides{}, nil)
+		config, err := clientConfig.ClientConfig()
+		if err != nil {
+			log.Printf("context %q atlanıyor, config oluşturulamadı: %v", contextName, err)
+			continue
+		}
+
+		c, err := buildCluster(contextName, config, r.wrapTransport)
+		if err != nil {
+			log.Printf("context %q atlanıyor: %v", contextName, err)
+			continue
+		}
+		clusters[contextName] = c
+	}
+
+	if len(clusters) == 0 {
+		return fmt.Errorf("kubeconfig içinde kullanılabilir context bulunamadı")
+	}
+
+	r.mu.Lock()
+	r.clusters = clusters
+	if _, ok := r.clusters[rawConfig.CurrentContext]; ok {
+		r.defaultName = rawConfig.CurrentContext
+	} else {
+		for name := range r.clusters {
+			r.defaultName = name
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Resolve returns the cluster for name. If name is empty, it falls back to
+// the calling MCP session's default cluster (set via use_cluster), or the
+// registry-wide default if that session has never called use_cluster.
+func (r *Registry) Resolve(ctx context.Context, name string) (*Cluster, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name == "" {
+		name = r.defaultName
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			if sessName, ok := r.sessionDefault[session.SessionID()]; ok {
+				name = sessName
+			}
+		}
+	}
+	c, ok := r.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("bilinmeyen cluster: %q", name)
+	}
+	return c, nil
+}
+
+// Ping probes the registry-wide default cluster's API server via a
+// DiscoveryClient call, so /healthz and /readyz reflect real API server
+// reachability rather than just the kubemcp process being up. It runs
+// outside any MCP session, so it always uses the registry-wide default
+// rather than a per-session one.
+func (r *Registry) Ping() error {
+	c, err := r.Resolve(context.Background(), "")
+	if err != nil {
+		return err
+	}
+	_, err = c.Discovery.ServerVersion()
+	return err
+}
+
+// List returns the known cluster names.
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.clusters))
+	for name := range r.clusters {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Use switches the default cluster that the calling MCP session's
+// no-cluster-argument calls fall back to. Other sessions are unaffected; a
+// call outside any MCP session (no ClientSession on ctx) falls back to
+// changing the registry-wide default instead.
+func (r *Registry) Use(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.clusters[name]; !ok {
+		return fmt.Errorf("bilinmeyen cluster: %q", name)
+	}
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		r.defaultName = name
+		return nil
+	}
+	r.sessionDefault[session.SessionID()] = name
+	return nil
+}
+
+// WatchForRotation checks the kubeconfig file's modification time every
+// interval and reloads every context from disk when it has changed, so
+// credential rotation written into the kubeconfig is picked up without a
+// restart, keeping long-lived SSE sessions alive across rotation. It is a
+// no-op when the registry was built from in-cluster config.
+func (r *Registry) WatchForRotation(ctx context.Context, interval time.Duration) {
+	if r.kubeconfigPath == "" {
+		return
+	}
+
+	var lastModTime time.Time
+	if info, err := os.Stat(r.kubeconfigPath); err == nil {
+		lastModTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.kubeconfigPath)
+			if err != nil {
+				continue
+			}
+			if !info.ModTime().After(lastModTime) {
+				continue
+			}
+			lastModTime = info.ModTime()
+
+			log.Println("kubeconfig değişikliği algılandı, cluster'lar yeniden yükleniyor...")
+			if err := r.loadFromKubeconfig(); err != nil {
+				log.Printf("kubeconfig yeniden yüklenemedi: %v", err)
+			}
+		}
+	}
+}
+
+// Register adds the list_clusters and use_cluster tools to the MCP server.
+func (r *Registry) Register(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("list_clusters",
+		mcp.WithDescription("Kayıtlı kubeconfig context'lerini (cluster'ları) listeler"),
+	), r.listClustersHandler)
+
+	s.AddTool(mcp.NewTool("use_cluster",
+		mcp.WithDescription("Varsayılan cluster'ı değiştirir; cluster argümanı verilmeyen çağrılar bu cluster'ı kullanır"),
+		mcp.WithString("cluster",
+			mcp.Description("Varsayılan yapılacak cluster/context ismi"),
+			mcp.Required(),
+		),
+	), r.useClusterHandler)
+}
+
+func (r *Registry) listClustersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.mu.RLock()
+	defaultName := r.defaultName
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		if sessName, ok := r.sessionDefault[session.SessionID()]; ok {
+			defaultName = sessName
+		}
+	}
+	r.mu.RUnlock()
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"clusters": r.List(),
+		"default":  defaultName,
+	})
+}
+
+func (r *Registry) useClusterHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	name, _ := args["cluster"].(string)
+	if name == "" {
+		return mcp.NewToolResultError("cluster is required"), nil
+	}
+
+	if err := r.Use(ctx, name); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultJSON(map[string]string{"default": name})
+}
+
+// ClusterArg reads the optional "cluster" argument out of a tool call's
+// arguments map, returning "" (the default cluster) when absent.
+func ClusterArg(args map[string]interface{}) string {
+	name, _ := args["cluster"].(string)
+	return name
+}
@@ -0,0 +1,129 @@
+// Package metrics provides the cross-cutting Prometheus instrumentation for
+// kubemcp: a per-tool latency/error wrapper for MCP handlers, and a
+// rest.Config transport wrapper that accounts every request kubemcp makes
+// against the Kubernetes API server. Together they let an operator tell
+// whether latency is in kubemcp, in mcp-go, or in the kube-apiserver.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds every metric kubemcp registers beyond the basic connection
+// gauge/counter main.go already had.
+type Metrics struct {
+	toolDuration *prometheus.HistogramVec
+	toolErrors   *prometheus.CounterVec
+
+	kubeAPIRequests *prometheus.CounterVec
+	kubeAPIDuration *prometheus.HistogramVec
+}
+
+// New builds a Metrics and registers its collectors against reg.
+func New(reg *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mcp_tool_duration_seconds",
+			Help:    "Latency of MCP tool invocations",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"tool", "status"}),
+		toolErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_tool_errors_total",
+			Help: "Total number of MCP tool invocations that returned an error",
+		}, []string{"tool"}),
+		kubeAPIRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kube_api_requests_total",
+			Help: "Total number of requests kubemcp made to the Kubernetes API server",
+		}, []string{"verb", "resource", "code"}),
+		kubeAPIDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kube_api_request_duration_seconds",
+			Help:    "Latency of requests kubemcp made to the Kubernetes API server",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"verb", "resource"}),
+	}
+
+	reg.MustRegister(m.toolDuration, m.toolErrors, m.kubeAPIRequests, m.kubeAPIDuration)
+
+	return m
+}
+
+// Wrap records mcp_tool_duration_seconds and mcp_tool_errors_total around a
+// tool handler, under the given tool name.
+func (m *Metrics) Wrap(tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, request)
+		status := "ok"
+		if err != nil || (result != nil && result.IsError) {
+			status = "error"
+			m.toolErrors.WithLabelValues(tool).Inc()
+		}
+		m.toolDuration.WithLabelValues(tool, status).Observe(time.Since(start).Seconds())
+		return result, err
+	}
+}
+
+// WrapTransport wraps a rest.Config's transport so every Kubernetes API call
+// is accounted in kube_api_requests_total / kube_api_request_duration_seconds.
+// It matches transport.WrapperFunc's signature, so it can be assigned
+// directly to rest.Config.WrapTransport.
+func (m *Metrics) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{wrapped: rt, metrics: m}
+}
+
+type instrumentedTransport struct {
+	wrapped http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.wrapped.RoundTrip(req)
+	duration := time.Since(start).Seconds()
+
+	verb := req.Method
+	resource := resourceFromPath(req.URL.Path)
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	t.metrics.kubeAPIRequests.WithLabelValues(verb, resource, code).Inc()
+	t.metrics.kubeAPIDuration.WithLabelValues(verb, resource).Observe(duration)
+
+	return resp, err
+}
+
+// resourceFromPath pulls the resource name out of a Kubernetes API path, e.g.
+// "/api/v1/namespaces/default/pods/foo" -> "pods" and
+// "/apis/apps/v1/deployments" -> "deployments".
+func resourceFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	start := 0
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		start = 2 // api, version
+	case len(segments) >= 3 && segments[0] == "apis":
+		start = 3 // apis, group, version
+	default:
+		return "unknown"
+	}
+
+	if start < len(segments) && segments[start] == "namespaces" {
+		start += 2 // namespaces, <name>
+	}
+
+	if start >= len(segments) {
+		return "unknown"
+	}
+	return segments[start]
+}
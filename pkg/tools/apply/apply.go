@@ -0,0 +1,329 @@
+// Package apply implements kubectl-apply-style manifest application as MCP
+// tools (kube_apply, kube_delete). Every object created through kube_apply is
+// stamped with a `kubemcp.io/session` label so a later kube_delete call can
+// garbage-collect exactly what a given session created.
+package apply
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/yildizozan/kubemcp/pkg/auth"
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+	"github.com/yildizozan/kubemcp/pkg/metrics"
+)
+
+// SessionLabel is the label stamped onto every object created through
+// kube_apply so kube_delete can later find exactly what a session created.
+const SessionLabel = "kubemcp.io/session"
+
+const fieldManager = "kubemcp"
+
+// Registrar resolves GVKs from a manifest into GVRs and applies/deletes them
+// through the dynamic client of whichever cluster a call targets.
+type Registrar struct {
+	clusters *cluster.Registry
+	metrics  *metrics.Metrics
+	auth     *auth.Gate
+}
+
+// NewRegistrar builds a Registrar backed by a cluster.Registry.
+func NewRegistrar(clusters *cluster.Registry, m *metrics.Metrics, g *auth.Gate) *Registrar {
+	return &Registrar{clusters: clusters, metrics: m, auth: g}
+}
+
+// Register adds the kube_apply and kube_delete tools to the MCP server.
+func (r *Registrar) Register(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("kube_apply",
+		mcp.WithDescription("YAML/JSON manifest'i (çoklu doküman olabilir) server-side apply ile uygular; her nesneyi bir session etiketiyle işaretler"),
+		mcp.WithString("manifest",
+			mcp.Description("Uygulanacak YAML/JSON manifest (--- ile ayrılmış çoklu doküman destekler)"),
+			mcp.Required(),
+		),
+		mcp.WithString("sessionID",
+			mcp.Description("Nesneleri etiketlemek için session kimliği (boşsa otomatik üretilir)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("true ise sunucu tarafında hiçbir şey kalıcı olarak değiştirilmez"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("kube_apply", r.kubeApplyHandler))
+
+	s.AddTool(mcp.NewTool("kube_delete",
+		mcp.WithDescription("kube_apply ile aynı sessionID'yle oluşturulmuş tüm nesneleri siler"),
+		mcp.WithString("sessionID",
+			mcp.Description("Silinecek nesnelerin session kimliği"),
+			mcp.Required(),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("kube_delete", r.kubeDeleteHandler))
+}
+
+// splitManifests splits a multi-document YAML/JSON manifest into individual
+// unstructured objects.
+func splitManifests(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(bytes.NewReader([]byte(manifest))), 4096)
+
+	var objs []*unstructured.Unstructured
+	for {
+		raw := map[string]interface{}{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("manifest parse edilemedi: %w", err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: raw})
+	}
+	return objs, nil
+}
+
+type objectStatus struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (r *Registrar) kubeApplyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	manifest, _ := args["manifest"].(string)
+	if manifest == "" {
+		return mcp.NewToolResultError("manifest is required"), nil
+	}
+	sessionID, _ := args["sessionID"].(string)
+	if sessionID == "" {
+		sessionID = uuid.NewString()
+	}
+	dryRun, _ := args["dryRun"].(bool)
+
+	objs, err := splitManifests(manifest)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	patchOptions := metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        boolPtr(true),
+	}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	results := make([]objectStatus, 0, len(objs))
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		status := objectStatus{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       gvk.Kind,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+		}
+
+		mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			status.Status = "error"
+			status.Error = fmt.Sprintf("kaynak haritalanamadı: %v", err)
+			results = append(results, status)
+			continue
+		}
+
+		namespaceForCheck := obj.GetNamespace()
+		if mapping.Scope.Name() == "namespace" && namespaceForCheck == "" {
+			namespaceForCheck = "default"
+		}
+		if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+			Namespace: namespaceForCheck,
+			Verb:      "patch",
+			Group:     mapping.Resource.Group,
+			Resource:  mapping.Resource.Resource,
+			Name:      obj.GetName(),
+		}); err != nil {
+			status.Status = "error"
+			status.Error = err.Error()
+			results = append(results, status)
+			continue
+		}
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[SessionLabel] = sessionID
+		obj.SetLabels(labels)
+
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			status.Status = "error"
+			status.Error = fmt.Sprintf("nesne serileştirilemedi: %v", err)
+			results = append(results, status)
+			continue
+		}
+
+		var resourceInterface dynamic.ResourceInterface
+		if mapping.Scope.Name() == "namespace" {
+			namespace := obj.GetNamespace()
+			if namespace == "" {
+				namespace = "default"
+			}
+			status.Namespace = namespace
+			resourceInterface = c.Dynamic.Resource(mapping.Resource).Namespace(namespace)
+		} else {
+			resourceInterface = c.Dynamic.Resource(mapping.Resource)
+		}
+
+		_, err = resourceInterface.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, patchOptions)
+		if err != nil {
+			status.Status = "error"
+			status.Error = err.Error()
+		} else if dryRun {
+			status.Status = "would-apply"
+		} else {
+			status.Status = "applied"
+		}
+		results = append(results, status)
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sessionID": sessionID,
+		"results":   results,
+	})
+}
+
+func (r *Registrar) kubeDeleteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	sessionID, _ := args["sessionID"].(string)
+	if sessionID == "" {
+		return mcp.NewToolResultError("sessionID is required"), nil
+	}
+
+	apiGroupResources, err := restmapper.GetAPIGroupResources(c.Discovery)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("API kaynakları keşfedilemedi: %v", err)), nil
+	}
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", SessionLabel, sessionID),
+	}
+
+	results := make([]objectStatus, 0)
+	for _, group := range apiGroupResources {
+		for version, resources := range group.VersionedResources {
+			for _, res := range resources {
+				if !contains(res.Verbs, "list") || !contains(res.Verbs, "delete") {
+					continue
+				}
+
+				gvr := schema.GroupVersionResource{Group: group.Group.Name, Version: version, Resource: res.Name}
+
+				list, err := c.Dynamic.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, listOptions)
+				if err != nil {
+					continue
+				}
+
+				for _, item := range list.Items {
+					ns := item.GetNamespace()
+
+					if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+						Namespace: ns,
+						Verb:      "delete",
+						Group:     gvr.Group,
+						Resource:  gvr.Resource,
+						Name:      item.GetName(),
+					}); err != nil {
+						// Caller isn't allowed to delete this object, so it
+						// doesn't get to learn it exists either: the
+						// identifying fields (name/namespace/kind) are
+						// withheld, not just the delete outcome.
+						results = append(results, objectStatus{
+							Status: "error",
+							Error:  err.Error(),
+						})
+						continue
+					}
+
+					var delErr error
+					if ns != "" {
+						delErr = c.Dynamic.Resource(gvr).Namespace(ns).Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+					} else {
+						delErr = c.Dynamic.Resource(gvr).Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+					}
+
+					status := objectStatus{
+						APIVersion: item.GetAPIVersion(),
+						Kind:       item.GetKind(),
+						Name:       item.GetName(),
+						Namespace:  ns,
+						Status:     "deleted",
+					}
+					if delErr != nil {
+						status.Status = "error"
+						status.Error = delErr.Error()
+					}
+					results = append(results, status)
+				}
+			}
+		}
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sessionID": sessionID,
+		"results":   results,
+	})
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
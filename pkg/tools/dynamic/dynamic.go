@@ -0,0 +1,345 @@
+// Package dynamic registers generic, discovery-driven MCP tools (list_resources,
+// get_resource, describe_resource, get_events_for_object) so kubemcp can query
+// any resource the API server exposes instead of only the handful of hardcoded
+// CoreV1 types in main.go.
+package dynamic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/yildizozan/kubemcp/pkg/auth"
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+	"github.com/yildizozan/kubemcp/pkg/metrics"
+)
+
+// Registrar resolves arbitrary apiVersion/kind arguments into GVRs and serves
+// them through the dynamic client of whichever cluster a call targets.
+type Registrar struct {
+	clusters *cluster.Registry
+	metrics  *metrics.Metrics
+	auth     *auth.Gate
+}
+
+// NewRegistrar builds a Registrar backed by a cluster.Registry.
+func NewRegistrar(clusters *cluster.Registry, m *metrics.Metrics, g *auth.Gate) *Registrar {
+	return &Registrar{clusters: clusters, metrics: m, auth: g}
+}
+
+// Register adds the dynamic resource tools to the MCP server.
+func (r *Registrar) Register(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("list_resources",
+		mcp.WithDescription("Belirtilen apiVersion/kind için kaynakları listeler (namespace, label/field selector destekler)"),
+		mcp.WithString("apiVersion",
+			mcp.Description("Kaynağın apiVersion değeri (örn. apps/v1, v1)"),
+			mcp.Required(),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Kaynağın kind değeri (örn. Deployment, ConfigMap)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (namespaced olmayan kaynaklar için yok sayılır)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label seçici"),
+		),
+		mcp.WithString("fieldSelector",
+			mcp.Description("Field seçici"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("list_resources", r.listResourcesHandler))
+
+	s.AddTool(mcp.NewTool("get_resource",
+		mcp.WithDescription("Belirtilen apiVersion/kind/name kaynağının tamamını getirir"),
+		mcp.WithString("apiVersion", mcp.Required()),
+		mcp.WithString("kind", mcp.Required()),
+		mcp.WithString("name", mcp.Required()),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (varsayılan: default)"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("get_resource", r.getResourceHandler))
+
+	s.AddTool(mcp.NewTool("describe_resource",
+		mcp.WithDescription("kubectl describe benzeri; kaynağın tamamını ve ilişkili event'lerini döner"),
+		mcp.WithString("apiVersion", mcp.Required()),
+		mcp.WithString("kind", mcp.Required()),
+		mcp.WithString("name", mcp.Required()),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (varsayılan: default)"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("describe_resource", r.describeResourceHandler))
+
+	s.AddTool(mcp.NewTool("get_events_for_object",
+		mcp.WithDescription("Belirtilen nesneye (involvedObject) ait Event'leri getirir"),
+		mcp.WithString("kind", mcp.Required()),
+		mcp.WithString("name", mcp.Required()),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (varsayılan: default)"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("get_events_for_object", r.getEventsForObjectHandler))
+}
+
+// resolveGVR maps an apiVersion+kind pair to a GVR and tells the caller whether
+// the resource is namespace-scoped, using the target cluster's cached RESTMapper.
+func resolveGVR(c *cluster.Cluster, apiVersion, kind string) (schema.GroupVersionResource, bool, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("geçersiz apiVersion %q: %w", apiVersion, err)
+	}
+
+	mapping, err := c.Mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("kaynak bulunamadı (%s/%s): %w", apiVersion, kind, err)
+	}
+
+	namespaced := mapping.Scope.Name() == "namespace"
+	return mapping.Resource, namespaced, nil
+}
+
+func (r *Registrar) listResourcesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	apiVersion, _ := args["apiVersion"].(string)
+	kind, _ := args["kind"].(string)
+	namespace, _ := args["namespace"].(string)
+	labelSelector, _ := args["labelSelector"].(string)
+	fieldSelector, _ := args["fieldSelector"].(string)
+
+	gvr, namespaced, err := resolveGVR(c, apiVersion, kind)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	checkNamespace := namespace
+	if !namespaced {
+		checkNamespace = ""
+	}
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: checkNamespace,
+		Verb:      "list",
+		Group:     gvr.Group,
+		Resource:  gvr.Resource,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	}
+
+	if namespaced {
+		res, err := c.Dynamic.Resource(gvr).Namespace(namespace).List(ctx, listOptions)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Kaynaklar listelenemedi: %v", err)), nil
+		}
+		return mcp.NewToolResultJSON(res.Items)
+	}
+
+	res, err := c.Dynamic.Resource(gvr).List(ctx, listOptions)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Kaynaklar listelenemedi: %v", err)), nil
+	}
+	return mcp.NewToolResultJSON(res.Items)
+}
+
+func (r *Registrar) getResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	apiVersion, _ := args["apiVersion"].(string)
+	kind, _ := args["kind"].(string)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	gvr, namespaced, err := resolveGVR(c, apiVersion, kind)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	checkNamespace := namespace
+	if !namespaced {
+		checkNamespace = ""
+	}
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: checkNamespace,
+		Verb:      "get",
+		Group:     gvr.Group,
+		Resource:  gvr.Resource,
+		Name:      name,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if namespaced {
+		obj, err := c.Dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Kaynak bulunamadı: %v", err)), nil
+		}
+		return mcp.NewToolResultJSON(obj.Object)
+	}
+
+	obj, err := c.Dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Kaynak bulunamadı: %v", err)), nil
+	}
+	return mcp.NewToolResultJSON(obj.Object)
+}
+
+func (r *Registrar) describeResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	apiVersion, _ := args["apiVersion"].(string)
+	kind, _ := args["kind"].(string)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	gvr, namespaced, err := resolveGVR(c, apiVersion, kind)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	checkNamespace := namespace
+	if !namespaced {
+		checkNamespace = ""
+	}
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: checkNamespace,
+		Verb:      "get",
+		Group:     gvr.Group,
+		Resource:  gvr.Resource,
+		Name:      name,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var obj map[string]interface{}
+	if namespaced {
+		u, err := c.Dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Kaynak bulunamadı: %v", err)), nil
+		}
+		obj = u.Object
+	} else {
+		u, err := c.Dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Kaynak bulunamadı: %v", err)), nil
+		}
+		obj = u.Object
+	}
+
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "list",
+		Resource:  "events",
+	}); err != nil {
+		return mcp.NewToolResultJSON(map[string]interface{}{
+			"object":    obj,
+			"eventsErr": err.Error(),
+		})
+	}
+
+	events, err := c.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", name, kind),
+	})
+	if err != nil {
+		return mcp.NewToolResultJSON(map[string]interface{}{
+			"object":    obj,
+			"eventsErr": fmt.Sprintf("Event'ler alınamadı: %v", err),
+		})
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"object": obj,
+		"events": events.Items,
+	})
+}
+
+func (r *Registrar) getEventsForObjectHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	kind, _ := args["kind"].(string)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	if kind == "" || name == "" {
+		return mcp.NewToolResultError("kind ve name zorunludur"), nil
+	}
+
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "list",
+		Resource:  "events",
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	events, err := c.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", name, kind),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Event'ler alınamadı: %v", err)), nil
+	}
+
+	return mcp.NewToolResultJSON(events.Items)
+}
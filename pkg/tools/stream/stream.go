@@ -0,0 +1,327 @@
+// Package stream adds get_pod_logs and exec_in_pod, the two MCP tools whose
+// output is pushed back to the client incrementally via MCP progress
+// notifications instead of a single NewToolResultJSON, because log/exec
+// output can be arbitrarily large or long-lived (follow, interactive exec).
+package stream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/yildizozan/kubemcp/pkg/auth"
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+	"github.com/yildizozan/kubemcp/pkg/metrics"
+)
+
+// Registrar adds the streaming log/exec tools and their Prometheus metrics.
+type Registrar struct {
+	clusters *cluster.Registry
+	mcp      *server.MCPServer
+	metrics  *metrics.Metrics
+	auth     *auth.Gate
+
+	streamBytesTotal *prometheus.CounterVec
+	activeStreams    prometheus.Gauge
+}
+
+// NewRegistrar builds a Registrar and registers its metrics against reg.
+func NewRegistrar(clusters *cluster.Registry, s *server.MCPServer, m *metrics.Metrics, reg *prometheus.Registry, g *auth.Gate) *Registrar {
+	r := &Registrar{
+		clusters: clusters,
+		mcp:      s,
+		metrics:  m,
+		auth:     g,
+		streamBytesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_stream_bytes_total",
+			Help: "Total bytes streamed back to MCP clients, by tool",
+		}, []string{"tool"}),
+		activeStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mcp_active_streams",
+			Help: "Number of currently active get_pod_logs/exec_in_pod streams",
+		}),
+	}
+
+	reg.MustRegister(r.streamBytesTotal)
+	reg.MustRegister(r.activeStreams)
+
+	return r
+}
+
+// Register adds the get_pod_logs and exec_in_pod tools to the MCP server.
+func (r *Registrar) Register(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("get_pod_logs",
+		mcp.WithDescription("Pod loglarını getirir; progressToken verilirse satırları ilerleme bildirimi olarak akıtır"),
+		mcp.WithString("podName", mcp.Required()),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (varsayılan: default)"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("Birden fazla container varsa hedef container adı"),
+		),
+		mcp.WithBoolean("follow",
+			mcp.Description("true ise log akışı sonlanmadan kapanmaz"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("sinceSeconds",
+			mcp.Description("Son N saniyedeki loglar"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description("Son N satır"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("get_pod_logs", r.getPodLogsHandler))
+
+	s.AddTool(mcp.NewTool("exec_in_pod",
+		mcp.WithDescription("Pod içinde komut çalıştırır; progressToken verilirse stdout/stderr'i ilerleme bildirimi olarak akıtır"),
+		mcp.WithString("podName", mcp.Required()),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (varsayılan: default)"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("Birden fazla container varsa hedef container adı"),
+		),
+		mcp.WithString("command",
+			mcp.Description("Çalıştırılacak komut (boşlukla ayrılmış)"),
+			mcp.Required(),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), r.metrics.Wrap("exec_in_pod", r.execInPodHandler))
+}
+
+// progressToken extracts the client-supplied progress token, if any, so
+// chunked output can be correlated back to the originating tool call.
+func progressToken(request mcp.CallToolRequest) (interface{}, bool) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil, false
+	}
+	return request.Params.Meta.ProgressToken, true
+}
+
+// maxBufferedOutput bounds how much of a get_pod_logs/exec_in_pod output
+// progressWriter keeps around for the tool call's final JSON result. Clients
+// that supplied a progressToken already received every byte as it was
+// written via notifications, so this is just a tail kept for convenience;
+// without it, a `follow: true` tail on a chatty pod (or a long-lived
+// exec_in_pod session) would buffer forever.
+const maxBufferedOutput = 64 * 1024
+
+// progressWriter turns writes into MCP progress notifications (when a
+// progress token was supplied) and always accounts the bytes in the
+// mcp_stream_bytes_total metric. Only the last maxBufferedOutput bytes are
+// kept for the final result; total tracks the true byte count regardless.
+type progressWriter struct {
+	ctx       context.Context
+	mcp       *server.MCPServer
+	token     interface{}
+	hasTok    bool
+	tool      string
+	bytes     *prometheus.CounterVec
+	total     int
+	buf       []byte
+	truncated bool
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.bytes.WithLabelValues(w.tool).Add(float64(len(p)))
+	w.total += len(p)
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > maxBufferedOutput {
+		w.buf = append([]byte(nil), w.buf[len(w.buf)-maxBufferedOutput:]...)
+		w.truncated = true
+	}
+
+	if w.hasTok {
+		_ = w.mcp.SendNotificationToClient(w.ctx, "notifications/progress", map[string]any{
+			"progressToken": w.token,
+			"progress":      w.total,
+			"message":       string(p),
+		})
+	}
+	return len(p), nil
+}
+
+// output returns the buffered tail together with a truncated flag, so the
+// final result can tell the caller they're not seeing the full body.
+func (w *progressWriter) output() (string, bool) {
+	return string(w.buf), w.truncated
+}
+
+func (r *Registrar) getPodLogsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	podName, _ := args["podName"].(string)
+	if podName == "" {
+		return mcp.NewToolResultError("podName is required"), nil
+	}
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	follow, _ := args["follow"].(bool)
+
+	opts := &v1.PodLogOptions{
+		Container: container,
+		Follow:    follow,
+	}
+	if since, ok := args["sinceSeconds"].(float64); ok {
+		v := int64(since)
+		opts.SinceSeconds = &v
+	}
+	if tail, ok := args["tailLines"].(float64); ok {
+		v := int64(tail)
+		opts.TailLines = &v
+	}
+
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "get",
+		Resource:    "pods",
+		Subresource: "log",
+		Name:        podName,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	podLogs, err := c.Clientset.CoreV1().Pods(namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Log akışı başlatılamadı: %v", err)), nil
+	}
+	defer podLogs.Close()
+
+	token, hasTok := progressToken(request)
+	w := &progressWriter{ctx: ctx, mcp: r.mcp, token: token, hasTok: hasTok, tool: "get_pod_logs", bytes: r.streamBytesTotal}
+
+	r.activeStreams.Inc()
+	defer r.activeStreams.Dec()
+
+	scanner := bufio.NewScanner(podLogs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return mcp.NewToolResultJSON(map[string]interface{}{
+				"status": "cancelled",
+				"bytes":  w.total,
+			})
+		default:
+		}
+		if _, err := w.Write(append(scanner.Bytes(), '\n')); err != nil {
+			break
+		}
+	}
+
+	output, truncated := w.output()
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"status":    "done",
+		"bytes":     w.total,
+		"output":    output,
+		"truncated": truncated,
+	})
+}
+
+func (r *Registrar) execInPodHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := r.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	podName, _ := args["podName"].(string)
+	if podName == "" {
+		return mcp.NewToolResultError("podName is required"), nil
+	}
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	commandStr, _ := args["command"].(string)
+	if commandStr == "" {
+		return mcp.NewToolResultError("command is required"), nil
+	}
+	command := strings.Fields(commandStr)
+
+	if err := r.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "create",
+		Resource:    "pods",
+		Subresource: "exec",
+		Name:        podName,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	execReq := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.RestConfig, "POST", execReq.URL())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Executor oluşturulamadı: %v", err)), nil
+	}
+
+	token, hasTok := progressToken(request)
+	stdout := &progressWriter{ctx: ctx, mcp: r.mcp, token: token, hasTok: hasTok, tool: "exec_in_pod", bytes: r.streamBytesTotal}
+	stderr := &progressWriter{ctx: ctx, mcp: r.mcp, token: token, hasTok: hasTok, tool: "exec_in_pod", bytes: r.streamBytesTotal}
+
+	r.activeStreams.Inc()
+	defer r.activeStreams.Dec()
+
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: io.Writer(stdout),
+		Stderr: io.Writer(stderr),
+	})
+
+	stdoutOutput, stdoutTruncated := stdout.output()
+	stderrOutput, stderrTruncated := stderr.output()
+	result := map[string]interface{}{
+		"stdout":    stdoutOutput,
+		"stderr":    stderrOutput,
+		"truncated": stdoutTruncated || stderrTruncated,
+		"bytes":     stdout.total + stderr.total,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+
+	return mcp.NewToolResultJSON(result)
+}
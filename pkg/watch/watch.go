@@ -0,0 +1,343 @@
+// Package watch maintains a per-cluster informer cache of Pods/Deployments/
+// Events. It backs two things: the `watch_resource` MCP tool, which streams
+// `notifications/resources/updated` events to subscribed clients as the
+// cache observes changes, and PodLister, which lets other tool handlers
+// (get_pod_details, get_pods_by_label) serve Pod reads from the cache instead
+// of hitting the API server on every call. Each cluster in the
+// cluster.Registry gets its own informer factory, started lazily the first
+// time a call targets it.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/yildizozan/kubemcp/pkg/auth"
+	"github.com/yildizozan/kubemcp/pkg/cluster"
+	"github.com/yildizozan/kubemcp/pkg/metrics"
+)
+
+// notificationsResourcesUpdated is the MCP notification method sent to
+// subscribed clients whenever a watched resource changes.
+const notificationsResourcesUpdated = "notifications/resources/updated"
+
+const informerResync = 30 * 1e9 // 30s
+
+type subscription struct {
+	id            string
+	sessionID     string
+	kind          string
+	namespace     string
+	labelSelector labels.Selector
+}
+
+// clusterWatcher owns a single cluster's informer factory and the
+// subscriptions registered against it.
+type clusterWatcher struct {
+	factory   informers.SharedInformerFactory
+	podLister corev1listers.PodLister
+	mcp       *server.MCPServer
+	stopCh    chan struct{}
+
+	mu   sync.RWMutex
+	subs map[string]*subscription
+}
+
+func newClusterWatcher(c *cluster.Cluster, s *server.MCPServer) (*clusterWatcher, error) {
+	cw := &clusterWatcher{
+		factory: informers.NewSharedInformerFactory(c.Clientset, informerResync),
+		mcp:     s,
+		stopCh:  make(chan struct{}),
+		subs:    make(map[string]*subscription),
+	}
+
+	pods := cw.factory.Core().V1().Pods()
+	cw.podLister = pods.Lister()
+	if _, err := pods.Informer().AddEventHandler(cw.handlerFor("Pod")); err != nil {
+		return nil, fmt.Errorf("pod informer handler eklenemedi: %w", err)
+	}
+
+	deployInformer := cw.factory.Apps().V1().Deployments().Informer()
+	if _, err := deployInformer.AddEventHandler(cw.handlerFor("Deployment")); err != nil {
+		return nil, fmt.Errorf("deployment informer handler eklenemedi: %w", err)
+	}
+
+	eventInformer := cw.factory.Core().V1().Events().Informer()
+	if _, err := eventInformer.AddEventHandler(cw.handlerFor("Event")); err != nil {
+		return nil, fmt.Errorf("event informer handler eklenemedi: %w", err)
+	}
+
+	cw.factory.Start(cw.stopCh)
+	cw.factory.WaitForCacheSync(cw.stopCh)
+
+	return cw, nil
+}
+
+func (cw *clusterWatcher) stop() {
+	select {
+	case <-cw.stopCh:
+	default:
+		close(cw.stopCh)
+	}
+}
+
+func (cw *clusterWatcher) handlerFor(kind string) cache.ResourceEventHandlerFuncs {
+	notify := func(action string, obj interface{}) {
+		meta, err := objectMetaOf(obj)
+		if err != nil {
+			return
+		}
+		cw.dispatch(kind, action, meta)
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify("ADDED", obj) },
+		UpdateFunc: func(_, newObj interface{}) { notify("MODIFIED", newObj) },
+		DeleteFunc: func(obj interface{}) { notify("DELETED", obj) },
+	}
+}
+
+// dispatch pushes a notification to every subscription whose kind/namespace/
+// label selector matches the changed object.
+func (cw *clusterWatcher) dispatch(kind, action string, meta objectMeta) {
+	cw.mu.RLock()
+	defer cw.mu.RUnlock()
+
+	for _, sub := range cw.subs {
+		if sub.kind != kind {
+			continue
+		}
+		if sub.namespace != "" && sub.namespace != meta.Namespace {
+			continue
+		}
+		if sub.labelSelector != nil && !sub.labelSelector.Matches(labels.Set(meta.Labels)) {
+			continue
+		}
+
+		err := cw.mcp.SendNotificationToSpecificClient(sub.sessionID, notificationsResourcesUpdated, map[string]any{
+			"subscriptionId": sub.id,
+			"kind":           kind,
+			"action":         action,
+			"name":           meta.Name,
+			"namespace":      meta.Namespace,
+		})
+		if err != nil {
+			log.Printf("watch_resource bildirimi gönderilemedi (subscription=%s): %v", sub.id, err)
+		}
+	}
+}
+
+func (cw *clusterWatcher) addSubscription(sub *subscription) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.subs[sub.id] = sub
+}
+
+// Manager lazily builds a clusterWatcher per cluster name the first time
+// watch_resource or PodLister targets it, and tears all of them down on Stop.
+type Manager struct {
+	clusters *cluster.Registry
+	mcp      *server.MCPServer
+	metrics  *metrics.Metrics
+	auth     *auth.Gate
+
+	mu       sync.Mutex
+	watchers map[string]*clusterWatcher
+}
+
+// NewManager builds a Manager backed by a cluster.Registry. No informer is
+// started until the first watch_resource or PodLister call for a given cluster.
+func NewManager(clusters *cluster.Registry, s *server.MCPServer, m *metrics.Metrics, g *auth.Gate) *Manager {
+	return &Manager{
+		clusters: clusters,
+		mcp:      s,
+		metrics:  m,
+		auth:     g,
+		watchers: make(map[string]*clusterWatcher),
+	}
+}
+
+// kindResources maps the kinds watch_resource supports to the core/apps
+// resource name SelfSubjectAccessReview expects.
+var kindResources = map[string]struct {
+	group    string
+	resource string
+}{
+	"Pod":        {resource: "pods"},
+	"Deployment": {group: "apps", resource: "deployments"},
+	"Event":      {resource: "events"},
+}
+
+// Start arranges for every cluster watcher to shut down when ctx is canceled.
+func (m *Manager) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		m.Stop()
+	}()
+	return nil
+}
+
+// Stop shuts down every cluster watcher started so far. Safe to call multiple times.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, cw := range m.watchers {
+		cw.stop()
+	}
+}
+
+// PodLister returns the informer-cache-backed PodLister for c, starting the
+// cluster's informer factory if this is the first call to target it.
+func (m *Manager) PodLister(c *cluster.Cluster) (corev1listers.PodLister, error) {
+	cw, err := m.watcherFor(c)
+	if err != nil {
+		return nil, err
+	}
+	return cw.podLister, nil
+}
+
+func (m *Manager) watcherFor(c *cluster.Cluster) (*clusterWatcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cw, ok := m.watchers[c.Name]; ok {
+		return cw, nil
+	}
+
+	cw, err := newClusterWatcher(c, m.mcp)
+	if err != nil {
+		return nil, err
+	}
+	m.watchers[c.Name] = cw
+	return cw, nil
+}
+
+// Register adds the watch_resource tool to the MCP server.
+func (m *Manager) Register(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("watch_resource",
+		mcp.WithDescription("Belirtilen kind/namespace/label için değişiklikleri izler ve notifications/resources/updated bildirimleri gönderir"),
+		mcp.WithString("kind",
+			mcp.Description("İzlenecek kaynak türü (Pod, Deployment veya Event)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (boş bırakılırsa tüm namespace'ler)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label seçici"),
+		),
+		mcp.WithString("cluster",
+			mcp.Description("Hedef cluster/context ismi (boşsa varsayılan cluster kullanılır)"),
+		),
+	), m.metrics.Wrap("watch_resource", m.watchResourceHandler))
+}
+
+func (m *Manager) watchResourceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid arguments format"), nil
+	}
+
+	c, err := m.clusters.Resolve(ctx, cluster.ClusterArg(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	kind, _ := args["kind"].(string)
+	namespace, _ := args["namespace"].(string)
+	labelSelectorStr, _ := args["labelSelector"].(string)
+
+	res, ok := kindResources[kind]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("desteklenmeyen kind: %s (Pod, Deployment, Event)", kind)), nil
+	}
+
+	if err := m.auth.Check(ctx, c, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "watch",
+		Group:     res.group,
+		Resource:  res.resource,
+	}); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var selector labels.Selector
+	if labelSelectorStr != "" {
+		parsed, err := labels.Parse(labelSelectorStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("geçersiz labelSelector: %v", err)), nil
+		}
+		selector = parsed
+	}
+
+	cw, err := m.watcherFor(c)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("cluster için izleme başlatılamadı: %v", err)), nil
+	}
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return mcp.NewToolResultError("watch_resource bir MCP session'ı gerektirir"), nil
+	}
+
+	sub := &subscription{
+		id:            uuid.NewString(),
+		sessionID:     session.SessionID(),
+		kind:          kind,
+		namespace:     namespace,
+		labelSelector: selector,
+	}
+	cw.addSubscription(sub)
+
+	return mcp.NewToolResultJSON(map[string]string{
+		"subscriptionId": sub.id,
+		"cluster":        c.Name,
+		"status":         "subscribed",
+	})
+}
+
+// objectMeta is the minimal set of fields dispatch needs out of the informer
+// payload, independent of the concrete API type.
+type objectMeta struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+}
+
+func objectMetaOf(obj interface{}) (objectMeta, error) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	type metaObject interface {
+		GetName() string
+		GetNamespace() string
+		GetLabels() map[string]string
+	}
+
+	m, ok := obj.(metaObject)
+	if !ok {
+		if ro, ok := obj.(runtime.Object); ok {
+			return objectMeta{}, fmt.Errorf("beklenmeyen nesne türü: %T", ro)
+		}
+		return objectMeta{}, fmt.Errorf("beklenmeyen nesne türü: %T", obj)
+	}
+
+	return objectMeta{
+		Name:      m.GetName(),
+		Namespace: m.GetNamespace(),
+		Labels:    m.GetLabels(),
+	}, nil
+}